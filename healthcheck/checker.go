@@ -0,0 +1,110 @@
+// Package healthcheck runs the periodic health checks configured on a backend.
+package healthcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/containous/traefik/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Checker performs a single health check against a backend server.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// NewChecker builds the Checker for serverURL described by cfg. When cfg.Type
+// is empty it defaults to the pre-existing HTTP check.
+func NewChecker(serverURL *url.URL, cfg *types.HealthCheck) (Checker, error) {
+	switch cfg.Type {
+	case "grpc":
+		return &grpcChecker{target: serverURL.Host, service: cfg.GRPCService, tls: cfg.TLS}, nil
+	case "tcp":
+		return &tcpChecker{address: serverURL.Host}, nil
+	case "", "http":
+		return &httpChecker{client: &http.Client{Timeout: 5 * time.Second}, url: serverURL}, nil
+	default:
+		return nil, fmt.Errorf("unsupported health check type %q", cfg.Type)
+	}
+}
+
+type httpChecker struct {
+	client *http.Client
+	url    *url.URL
+}
+
+func (c *httpChecker) Check(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("received non-2xx/3xx status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// tcpChecker reports a server healthy if a TCP connection can be established.
+type tcpChecker struct {
+	address string
+}
+
+func (c *tcpChecker) Check(ctx context.Context) error {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// grpcChecker speaks the standard gRPC Health Checking Protocol
+// (grpc.health.v1.Health/Check), treating SERVING as healthy.
+type grpcChecker struct {
+	target  string
+	service string
+	tls     *types.ClientTLS
+}
+
+func (c *grpcChecker) Check(ctx context.Context) error {
+	opts := []grpc.DialOption{grpc.WithBlock()}
+	if c.tls != nil {
+		creds := credentials.NewTLS(&tls.Config{InsecureSkipVerify: c.tls.InsecureSkipVerify})
+		opts = append(opts, grpc.WithTransportCredentials(creds))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	conn, err := grpc.DialContext(ctx, c.target, opts...)
+	if err != nil {
+		return fmt.Errorf("unable to dial %s: %v", c.target, err)
+	}
+	defer conn.Close()
+
+	client := grpc_health_v1.NewHealthClient(conn)
+	resp, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: c.service})
+	if err != nil {
+		return fmt.Errorf("grpc health check failed: %v", err)
+	}
+
+	if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		return fmt.Errorf("service %q is not serving: %s", c.service, resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package healthcheck
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/containous/traefik/types"
+)
+
+func TestHTTPChecker_HealthyOn2xx(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+
+	checker := &httpChecker{client: server.Client(), url: serverURL}
+
+	if err := checker.Check(context.Background()); err != nil {
+		t.Errorf("expected a 2xx response to be healthy, got %v", err)
+	}
+}
+
+func TestHTTPChecker_UnhealthyOn5xx(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+
+	checker := &httpChecker{client: server.Client(), url: serverURL}
+
+	if err := checker.Check(context.Background()); err == nil {
+		t.Error("expected a 5xx response to be unhealthy")
+	}
+}
+
+func TestHTTPChecker_UnhealthyOnConnectionFailure(t *testing.T) {
+	serverURL, err := url.Parse("http://127.0.0.1:1")
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+
+	checker := &httpChecker{client: &http.Client{}, url: serverURL}
+
+	if err := checker.Check(context.Background()); err == nil {
+		t.Error("expected a connection failure to be unhealthy")
+	}
+}
+
+func TestTCPChecker_HealthyWhenListening(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen: %v", err)
+	}
+	defer listener.Close()
+
+	checker := &tcpChecker{address: listener.Addr().String()}
+
+	if err := checker.Check(context.Background()); err != nil {
+		t.Errorf("expected a listening address to be healthy, got %v", err)
+	}
+}
+
+func TestTCPChecker_UnhealthyWhenNothingListening(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	checker := &tcpChecker{address: addr}
+
+	if err := checker.Check(context.Background()); err == nil {
+		t.Error("expected a closed port to be unhealthy")
+	}
+}
+
+func TestNewChecker_DispatchesByType(t *testing.T) {
+	serverURL, err := url.Parse("http://example.com")
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+
+	tests := []struct {
+		checkType string
+		want      interface{}
+	}{
+		{"", &httpChecker{}},
+		{"http", &httpChecker{}},
+		{"tcp", &tcpChecker{}},
+		{"grpc", &grpcChecker{}},
+	}
+
+	for _, tt := range tests {
+		checker, err := NewChecker(serverURL, &types.HealthCheck{Type: tt.checkType})
+		if err != nil {
+			t.Fatalf("NewChecker(%q): %v", tt.checkType, err)
+		}
+
+		switch tt.want.(type) {
+		case *httpChecker:
+			if _, ok := checker.(*httpChecker); !ok {
+				t.Errorf("NewChecker(%q): expected *httpChecker, got %T", tt.checkType, checker)
+			}
+		case *tcpChecker:
+			if _, ok := checker.(*tcpChecker); !ok {
+				t.Errorf("NewChecker(%q): expected *tcpChecker, got %T", tt.checkType, checker)
+			}
+		case *grpcChecker:
+			if _, ok := checker.(*grpcChecker); !ok {
+				t.Errorf("NewChecker(%q): expected *grpcChecker, got %T", tt.checkType, checker)
+			}
+		}
+	}
+}
+
+func TestNewChecker_RejectsUnsupportedType(t *testing.T) {
+	serverURL, err := url.Parse("http://example.com")
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+
+	if _, err := NewChecker(serverURL, &types.HealthCheck{Type: "bogus"}); err == nil {
+		t.Error("expected an error for an unsupported health check type")
+	}
+}
@@ -0,0 +1,179 @@
+// Package loadbalancer implements consistent-hash based load balancing for backends.
+package loadbalancer
+
+import (
+	"fmt"
+	"hash/crc32"
+	"math"
+	"sort"
+	"sync"
+)
+
+// defaultVirtualNodes is the number of points a server with weight 1 gets on
+// the ring. Higher values smooth the distribution at the cost of more memory
+// and slower rebalancing.
+const defaultVirtualNodes = 100
+
+// maxWeight bounds how many virtual nodes a single server can add to the
+// ring, so that a misconfigured per-server weight label cannot make Add
+// block every other backend sharing this Ring for an unbounded amount of time.
+const maxWeight = 1000
+
+// Ring is a consistent-hash ring implementing Google's "consistent hashing
+// with bounded loads": each server's in-flight load is capped at
+// ceil(average * c), so that when a shard is saturated, traffic spills over
+// to the next server on the ring instead of piling up, while still
+// preferring the same server for a given key whenever it has spare capacity.
+type Ring struct {
+	mu sync.Mutex
+
+	// c bounds how far above the weighted-average load a single server may
+	// go before Get starts probing the ring for another candidate. c must be >= 1.
+	c float64
+
+	weights     map[string]int
+	load        map[string]int64
+	totalWeight int
+	totalLoad   int64
+
+	hashes     []uint32
+	hashToNode map[uint32]string
+}
+
+// NewRing creates a Ring with bounded-load factor c. c must be >= 1; a value
+// close to 1 enforces near-perfectly even load at the cost of more ring
+// probing, while larger values allow more skew toward the preferred server.
+func NewRing(c float64) (*Ring, error) {
+	if c < 1 {
+		return nil, fmt.Errorf("bounded load factor must be >= 1, got %v", c)
+	}
+
+	return &Ring{
+		c:          c,
+		weights:    make(map[string]int),
+		load:       make(map[string]int64),
+		hashToNode: make(map[uint32]string),
+	}, nil
+}
+
+// Add inserts node into the ring with the given weight, or updates its
+// weight if already present, preserving its current in-flight load.
+// weight is clamped to [1, maxWeight] so a single Add cannot stall the ring
+// by generating an unbounded number of virtual nodes.
+func (r *Ring) Add(node string, weight int) {
+	if weight < 1 {
+		weight = 1
+	}
+	if weight > maxWeight {
+		weight = maxWeight
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.removeLocked(node, false)
+
+	r.weights[node] = weight
+	r.totalWeight += weight
+
+	for i := 0; i < weight*defaultVirtualNodes; i++ {
+		h := hashKey(fmt.Sprintf("%s#%d", node, i))
+		r.hashToNode[h] = node
+	}
+
+	r.rebuildHashesLocked()
+}
+
+// Remove takes node out of the ring, dropping any load it was carrying.
+func (r *Ring) Remove(node string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.removeLocked(node, true)
+	r.rebuildHashesLocked()
+}
+
+// removeLocked drops node's weight and ring entries. When dropLoad is false
+// (re-adding a node to change its weight), the node's in-flight load and its
+// contribution to totalLoad are left untouched, so capacityLocked keeps
+// accounting for requests that are still being served on it.
+func (r *Ring) removeLocked(node string, dropLoad bool) {
+	if weight, ok := r.weights[node]; ok {
+		r.totalWeight -= weight
+		delete(r.weights, node)
+	}
+	if dropLoad {
+		r.totalLoad -= r.load[node]
+		delete(r.load, node)
+	}
+
+	for h, n := range r.hashToNode {
+		if n == node {
+			delete(r.hashToNode, h)
+		}
+	}
+}
+
+func (r *Ring) rebuildHashesLocked() {
+	hashes := make([]uint32, 0, len(r.hashToNode))
+	for h := range r.hashToNode {
+		hashes = append(hashes, h)
+	}
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i] < hashes[j] })
+	r.hashes = hashes
+}
+
+// Get returns the node that key should be routed to, walking the ring past
+// the preferred server for key whenever it is over its bounded-load capacity.
+func (r *Ring) Get(key string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.hashes) == 0 {
+		return "", fmt.Errorf("loadbalancer: ring is empty")
+	}
+
+	start := r.search(hashKey(key))
+
+	for i := 0; i < len(r.hashes); i++ {
+		node := r.hashToNode[r.hashes[(start+i)%len(r.hashes)]]
+		if r.load[node] < r.capacityLocked(node) {
+			r.load[node]++
+			r.totalLoad++
+			return node, nil
+		}
+	}
+
+	return "", fmt.Errorf("loadbalancer: no server under capacity")
+}
+
+// Done releases the load unit acquired by a prior Get for node, once the
+// request it served has completed.
+func (r *Ring) Done(node string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.load[node] > 0 {
+		r.load[node]--
+		r.totalLoad--
+	}
+}
+
+// capacityLocked returns the maximum in-flight load node may carry, given the
+// current total load across the ring, proportional to its weight.
+func (r *Ring) capacityLocked(node string) int64 {
+	if r.totalWeight == 0 {
+		return 0
+	}
+
+	average := float64(r.totalLoad+1) * float64(r.weights[node]) / float64(r.totalWeight)
+	return int64(math.Ceil(average * r.c))
+}
+
+func (r *Ring) search(h uint32) int {
+	return sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+}
+
+func hashKey(key string) uint32 {
+	return crc32.ChecksumIEEE([]byte(key))
+}
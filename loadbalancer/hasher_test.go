@@ -0,0 +1,154 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRing_GetIsStableAcrossCalls(t *testing.T) {
+	ring, err := NewRing(1.25)
+	if err != nil {
+		t.Fatalf("NewRing: %v", err)
+	}
+	ring.Add("server1", 1)
+	ring.Add("server2", 1)
+	ring.Add("server3", 1)
+
+	first, err := ring.Get("some-session-key")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	ring.Done(first)
+
+	second, err := ring.Get("some-session-key")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	if first != second {
+		t.Errorf("expected the same server for repeated lookups of an idle key, got %q then %q", first, second)
+	}
+}
+
+func TestRing_RebalancesOnAdd(t *testing.T) {
+	ring, err := NewRing(1.25)
+	if err != nil {
+		t.Fatalf("NewRing: %v", err)
+	}
+	ring.Add("server1", 1)
+	ring.Add("server2", 1)
+
+	keys := make([]string, 200)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+	}
+
+	before := make(map[string]string, len(keys))
+	for _, key := range keys {
+		node, err := ring.Get(key)
+		if err != nil {
+			t.Fatalf("Get(%q): %v", key, err)
+		}
+		ring.Done(node)
+		before[key] = node
+	}
+
+	ring.Add("server3", 1)
+
+	moved := 0
+	for _, key := range keys {
+		node, err := ring.Get(key)
+		if err != nil {
+			t.Fatalf("Get(%q): %v", key, err)
+		}
+		ring.Done(node)
+		if node != before[key] {
+			moved++
+		}
+	}
+
+	// Consistent hashing should only remap a minority of keys onto the new server.
+	if moved == 0 || moved > len(keys)/2 {
+		t.Errorf("expected a minority of keys to move after adding a server, moved %d/%d", moved, len(keys))
+	}
+}
+
+func TestRing_RemoveStopsRoutingToNode(t *testing.T) {
+	ring, err := NewRing(1.25)
+	if err != nil {
+		t.Fatalf("NewRing: %v", err)
+	}
+	ring.Add("server1", 1)
+	ring.Add("server2", 1)
+
+	ring.Remove("server2")
+
+	for i := 0; i < 50; i++ {
+		node, err := ring.Get(fmt.Sprintf("key-%d", i))
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		ring.Done(node)
+		if node == "server2" {
+			t.Fatalf("removed server2 still received traffic")
+		}
+	}
+}
+
+func TestRing_BoundedLoadSpillsOverWhenSaturated(t *testing.T) {
+	ring, err := NewRing(1.0)
+	if err != nil {
+		t.Fatalf("NewRing: %v", err)
+	}
+	ring.Add("server1", 1)
+	ring.Add("server2", 1)
+
+	seen := make(map[string]int)
+	var acquired []string
+	for i := 0; i < 10; i++ {
+		node, err := ring.Get("hot-key")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		seen[node]++
+		acquired = append(acquired, node)
+	}
+
+	if len(seen) < 2 {
+		t.Errorf("expected saturated preferred server to spill over to another node, got distribution %v", seen)
+	}
+
+	for _, node := range acquired {
+		ring.Done(node)
+	}
+}
+
+func TestRing_ReweightPreservesInFlightLoad(t *testing.T) {
+	ring, err := NewRing(1.0)
+	if err != nil {
+		t.Fatalf("NewRing: %v", err)
+	}
+	ring.Add("server1", 1)
+	ring.Add("server2", 1)
+
+	node, err := ring.Get("hot-key")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	// Re-add server1 to bump its weight while it still has the in-flight
+	// request acquired above; its load must not be reset to zero.
+	ring.Add("server1", 2)
+
+	if got := ring.load[node]; got != 1 {
+		t.Errorf("expected in-flight load on %q to survive a re-weight, got %d", node, got)
+	}
+
+	ring.Done(node)
+}
+
+func TestNewRing_RejectsSubOneBoundedLoadFactor(t *testing.T) {
+	if _, err := NewRing(0.5); err == nil {
+		t.Error("expected an error for a bounded load factor below 1")
+	}
+}
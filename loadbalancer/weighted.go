@@ -0,0 +1,94 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"sync"
+)
+
+// WeightedRoundRobin selects servers using Nginx's smooth weighted
+// round-robin algorithm: each server accumulates its weight every pick, the
+// highest accumulator is chosen and then reduced by the total weight, which
+// spreads picks evenly across a pick cycle instead of bursting through a
+// single high-weight server before moving to the next one.
+type WeightedRoundRobin struct {
+	mu sync.Mutex
+
+	order       []string
+	weights     map[string]int
+	current     map[string]int
+	totalWeight int
+}
+
+// NewWeightedRoundRobin creates an empty WeightedRoundRobin.
+func NewWeightedRoundRobin() *WeightedRoundRobin {
+	return &WeightedRoundRobin{
+		weights: make(map[string]int),
+		current: make(map[string]int),
+	}
+}
+
+// Add inserts node with the given weight, or updates its weight if already
+// present. weight <= 0 is treated as 1.
+func (w *WeightedRoundRobin) Add(node string, weight int) {
+	if weight <= 0 {
+		weight = 1
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.weights[node]; !ok {
+		w.order = append(w.order, node)
+	} else {
+		w.totalWeight -= w.weights[node]
+	}
+
+	w.weights[node] = weight
+	w.totalWeight += weight
+}
+
+// Remove takes node out of the rotation.
+func (w *WeightedRoundRobin) Remove(node string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if weight, ok := w.weights[node]; ok {
+		w.totalWeight -= weight
+		delete(w.weights, node)
+		delete(w.current, node)
+
+		for i, n := range w.order {
+			if n == node {
+				w.order = append(w.order[:i], w.order[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// Next returns the next server in the weighted rotation.
+func (w *WeightedRoundRobin) Next() (string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.order) == 0 {
+		return "", fmt.Errorf("loadbalancer: no server configured")
+	}
+
+	var best string
+	bestCurrent := 0
+	first := true
+
+	for _, node := range w.order {
+		w.current[node] += w.weights[node]
+		if first || w.current[node] > bestCurrent {
+			best = node
+			bestCurrent = w.current[node]
+			first = false
+		}
+	}
+
+	w.current[best] -= w.totalWeight
+
+	return best, nil
+}
@@ -0,0 +1,87 @@
+package loadbalancer
+
+import "testing"
+
+func TestWeightedRoundRobin_DistributesProportionallyToWeight(t *testing.T) {
+	wrr := NewWeightedRoundRobin()
+	wrr.Add("server1", 1)
+	wrr.Add("server2", 3)
+
+	counts := make(map[string]int)
+	for i := 0; i < 8; i++ {
+		node, err := wrr.Next()
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		counts[node]++
+	}
+
+	if counts["server1"] != 2 {
+		t.Errorf("expected server1 (weight 1) to be picked 2/8 times, got %d", counts["server1"])
+	}
+	if counts["server2"] != 6 {
+		t.Errorf("expected server2 (weight 3) to be picked 6/8 times, got %d", counts["server2"])
+	}
+}
+
+func TestWeightedRoundRobin_SmoothsConsecutivePicks(t *testing.T) {
+	wrr := NewWeightedRoundRobin()
+	wrr.Add("server1", 5)
+	wrr.Add("server2", 1)
+	wrr.Add("server3", 1)
+
+	// With weights 5:1:1 a naive (non-smooth) round robin would pick server1
+	// five times in a row before ever reaching server2/server3. The smooth
+	// algorithm interleaves picks instead, so within a single pick cycle
+	// (totalWeight picks) no server should be picked more than twice in a row.
+	var picks []string
+	for i := 0; i < 7; i++ {
+		node, err := wrr.Next()
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		picks = append(picks, node)
+	}
+
+	longestRun, run := 1, 1
+	for i := 1; i < len(picks); i++ {
+		if picks[i] == picks[i-1] {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longestRun {
+			longestRun = run
+		}
+	}
+
+	if longestRun > 2 {
+		t.Errorf("expected smooth weighted round robin to avoid long bursts, longest run was %d: %v", longestRun, picks)
+	}
+}
+
+func TestWeightedRoundRobin_RemoveStopsRoutingToNode(t *testing.T) {
+	wrr := NewWeightedRoundRobin()
+	wrr.Add("server1", 1)
+	wrr.Add("server2", 1)
+
+	wrr.Remove("server2")
+
+	for i := 0; i < 10; i++ {
+		node, err := wrr.Next()
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		if node == "server2" {
+			t.Fatalf("removed server2 still received traffic")
+		}
+	}
+}
+
+func TestWeightedRoundRobin_NextOnEmptyReturnsError(t *testing.T) {
+	wrr := NewWeightedRoundRobin()
+
+	if _, err := wrr.Next(); err == nil {
+		t.Error("expected an error when no server is configured")
+	}
+}
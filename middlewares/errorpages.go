@@ -0,0 +1,53 @@
+package middlewares
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/types"
+)
+
+// RenderErrorPageTemplate renders page.Template with an ErrorPageContext
+// built from req, statusCode, requestID and, when page.PassUpstreamBody is
+// set, upstreamBody. It writes the rendered body and status to rw. Callers
+// only take this path when page.Template is non-empty; the status/query/
+// backend labels keep sub-requesting the error backend as before.
+//
+// page.Template is parsed with html/template rather than text/template so
+// that {{.Host}} (attacker-controlled via the Host header) and
+// {{.UpstreamBody}} get contextual HTML escaping instead of being rendered verbatim.
+func RenderErrorPageTemplate(rw http.ResponseWriter, req *http.Request, statusCode int, requestID string, upstreamBody []byte, page *types.ErrorPage) error {
+	tmpl, err := template.New("errorPage").Parse(page.Template)
+	if err != nil {
+		return err
+	}
+
+	ctx := types.ErrorPageContext{
+		StatusCode: statusCode,
+		RequestID:  requestID,
+		Host:       req.Host,
+	}
+	if page.PassUpstreamBody {
+		ctx.UpstreamBody = string(upstreamBody)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, ctx); err != nil {
+		return err
+	}
+
+	contentType := page.ContentType
+	if len(contentType) == 0 {
+		contentType = "text/html; charset=utf-8"
+	}
+
+	rw.Header().Set("Content-Type", contentType)
+	rw.WriteHeader(statusCode)
+	if _, err := rw.Write(buf.Bytes()); err != nil {
+		log.Errorf("Unable to write rendered error page: %v", err)
+	}
+
+	return nil
+}
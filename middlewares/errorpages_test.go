@@ -0,0 +1,82 @@
+package middlewares
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/containous/traefik/types"
+)
+
+func TestRenderErrorPageTemplate_EscapesAttackerControlledHost(t *testing.T) {
+	page := &types.ErrorPage{
+		Template: `<html><body>{{.StatusCode}} on {{.Host}}</body></html>`,
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Host = `<script>alert(1)</script>`
+
+	rw := httptest.NewRecorder()
+
+	if err := RenderErrorPageTemplate(rw, req, 503, "req-1", nil, page); err != nil {
+		t.Fatalf("RenderErrorPageTemplate: %v", err)
+	}
+
+	body := rw.Body.String()
+	if strings.Contains(body, "<script>") {
+		t.Errorf("expected the Host header to be HTML-escaped, got unescaped body: %s", body)
+	}
+	if !strings.Contains(body, "&lt;script&gt;") {
+		t.Errorf("expected an escaped rendering of the Host header, got: %s", body)
+	}
+}
+
+func TestRenderErrorPageTemplate_EscapesUpstreamBodyWhenPassed(t *testing.T) {
+	page := &types.ErrorPage{
+		Template:         `<div>{{.UpstreamBody}}</div>`,
+		PassUpstreamBody: true,
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	rw := httptest.NewRecorder()
+
+	upstreamBody := []byte(`<img src=x onerror=alert(1)>`)
+	if err := RenderErrorPageTemplate(rw, req, 502, "req-2", upstreamBody, page); err != nil {
+		t.Fatalf("RenderErrorPageTemplate: %v", err)
+	}
+
+	body := rw.Body.String()
+	if strings.Contains(body, "<img") {
+		t.Errorf("expected the upstream body to be HTML-escaped, got unescaped body: %s", body)
+	}
+}
+
+func TestRenderErrorPageTemplate_DefaultsContentType(t *testing.T) {
+	page := &types.ErrorPage{Template: `ok`}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	rw := httptest.NewRecorder()
+
+	if err := RenderErrorPageTemplate(rw, req, 500, "req-3", nil, page); err != nil {
+		t.Fatalf("RenderErrorPageTemplate: %v", err)
+	}
+
+	if got := rw.Header().Get("Content-Type"); got != "text/html; charset=utf-8" {
+		t.Errorf("expected default Content-Type, got %q", got)
+	}
+}
+
+func TestRenderErrorPageTemplate_HonorsExplicitContentType(t *testing.T) {
+	page := &types.ErrorPage{Template: `{"status":{{.StatusCode}}}`, ContentType: "application/json"}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	rw := httptest.NewRecorder()
+
+	if err := RenderErrorPageTemplate(rw, req, 500, "req-4", nil, page); err != nil {
+		t.Fatalf("RenderErrorPageTemplate: %v", err)
+	}
+
+	if got := rw.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("expected explicit Content-Type to be honored, got %q", got)
+	}
+}
@@ -0,0 +1,73 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/containous/traefik/types"
+)
+
+// ModifyRequestHeaders applies the add/remove/rename operations described by
+// cfg's CustomRequestHeaders and RequestHeaders to req, in that order:
+// remove, then rename, then the static CustomRequestHeaders overwrite, then
+// add. Removing or renaming a header first means a later set/add can never
+// be clobbered by stale upstream-set values.
+func ModifyRequestHeaders(req *http.Request, cfg *types.Headers) {
+	if cfg == nil {
+		return
+	}
+	removeAndRename(req.Header, cfg.RequestHeaders)
+	setCustomHeaders(req.Header, cfg.CustomRequestHeaders)
+	addHeaders(req.Header, cfg.RequestHeaders)
+}
+
+// ModifyResponseHeaders applies cfg's CustomResponseHeaders and
+// ResponseHeaders operations to resp, following the same remove, rename,
+// set, add precedence as ModifyRequestHeaders.
+func ModifyResponseHeaders(resp *http.Response, cfg *types.Headers) {
+	if cfg == nil {
+		return
+	}
+	removeAndRename(resp.Header, cfg.ResponseHeaders)
+	setCustomHeaders(resp.Header, cfg.CustomResponseHeaders)
+	addHeaders(resp.Header, cfg.ResponseHeaders)
+}
+
+// removeAndRename applies, in order, the remove then rename steps of the
+// remove/rename/set/add precedence.
+func removeAndRename(header http.Header, manipulation *types.HeaderManipulation) {
+	if manipulation == nil {
+		return
+	}
+
+	for _, name := range manipulation.Remove {
+		header.Del(name)
+	}
+
+	for from, to := range manipulation.Rename {
+		if value := header.Get(from); value != "" {
+			header.Set(to, value)
+			header.Del(from)
+		}
+	}
+}
+
+// addHeaders applies the final, lowest-precedence add step.
+func addHeaders(header http.Header, manipulation *types.HeaderManipulation) {
+	if manipulation == nil {
+		return
+	}
+
+	for name, value := range manipulation.Add {
+		header.Add(name, value)
+	}
+}
+
+func setCustomHeaders(header http.Header, custom map[string]string) {
+	for name, value := range custom {
+		if len(value) == 0 {
+			header.Del(name)
+			continue
+		}
+		header.Set(name, value)
+	}
+}
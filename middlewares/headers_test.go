@@ -0,0 +1,127 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/types"
+)
+
+func TestModifyRequestHeaders_NilConfigIsNoop(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Existing", "value")
+
+	ModifyRequestHeaders(req, nil)
+
+	if req.Header.Get("X-Existing") != "value" {
+		t.Error("expected headers to be left untouched when cfg is nil")
+	}
+}
+
+func TestModifyRequestHeaders_RemoveThenRenameThenSetThenAdd(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Remove-Me", "secret")
+	req.Header.Set("X-Old-Name", "renamed-value")
+
+	cfg := &types.Headers{
+		CustomRequestHeaders: map[string]string{
+			"X-Static": "overwritten",
+		},
+		RequestHeaders: &types.HeaderManipulation{
+			Remove: []string{"X-Remove-Me"},
+			Rename: map[string]string{"X-Old-Name": "X-New-Name"},
+			Add:    map[string]string{"X-Static": "from-add", "X-Extra": "extra-value"},
+		},
+	}
+
+	ModifyRequestHeaders(req, cfg)
+
+	if req.Header.Get("X-Remove-Me") != "" {
+		t.Error("expected X-Remove-Me to be removed")
+	}
+	if req.Header.Get("X-Old-Name") != "" {
+		t.Error("expected X-Old-Name to be removed by rename")
+	}
+	if req.Header.Get("X-New-Name") != "renamed-value" {
+		t.Errorf("expected X-New-Name to carry the renamed value, got %q", req.Header.Get("X-New-Name"))
+	}
+	if got := req.Header.Values("X-Static"); len(got) != 2 || got[0] != "overwritten" || got[1] != "from-add" {
+		t.Errorf("expected CustomRequestHeaders to set X-Static then add to append a second value, got %v", got)
+	}
+	if req.Header.Get("X-Extra") != "extra-value" {
+		t.Errorf("expected X-Extra to be added, got %q", req.Header.Get("X-Extra"))
+	}
+}
+
+func TestModifyRequestHeaders_RenameSkipsAbsentSourceHeader(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+
+	cfg := &types.Headers{
+		RequestHeaders: &types.HeaderManipulation{
+			Rename: map[string]string{"X-Absent": "X-Target"},
+		},
+	}
+
+	ModifyRequestHeaders(req, cfg)
+
+	if req.Header.Get("X-Target") != "" {
+		t.Error("expected no rename to occur when the source header is absent")
+	}
+}
+
+func TestModifyRequestHeaders_EmptyCustomHeaderValueDeletes(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Drop-Me", "value")
+
+	cfg := &types.Headers{
+		CustomRequestHeaders: map[string]string{"X-Drop-Me": ""},
+	}
+
+	ModifyRequestHeaders(req, cfg)
+
+	if req.Header.Get("X-Drop-Me") != "" {
+		t.Error("expected an empty CustomRequestHeaders value to delete the header")
+	}
+}
+
+func TestModifyResponseHeaders_RemoveThenRenameThenSetThenAdd(t *testing.T) {
+	resp := &http.Response{Header: make(http.Header)}
+	resp.Header.Set("X-Remove-Me", "secret")
+	resp.Header.Set("X-Old-Name", "renamed-value")
+
+	cfg := &types.Headers{
+		CustomResponseHeaders: map[string]string{"X-Static": "overwritten"},
+		ResponseHeaders: &types.HeaderManipulation{
+			Remove: []string{"X-Remove-Me"},
+			Rename: map[string]string{"X-Old-Name": "X-New-Name"},
+			Add:    map[string]string{"X-Extra": "extra-value"},
+		},
+	}
+
+	ModifyResponseHeaders(resp, cfg)
+
+	if resp.Header.Get("X-Remove-Me") != "" {
+		t.Error("expected X-Remove-Me to be removed")
+	}
+	if resp.Header.Get("X-New-Name") != "renamed-value" {
+		t.Errorf("expected X-New-Name to carry the renamed value, got %q", resp.Header.Get("X-New-Name"))
+	}
+	if resp.Header.Get("X-Static") != "overwritten" {
+		t.Errorf("expected X-Static to be set, got %q", resp.Header.Get("X-Static"))
+	}
+	if resp.Header.Get("X-Extra") != "extra-value" {
+		t.Errorf("expected X-Extra to be added, got %q", resp.Header.Get("X-Extra"))
+	}
+}
+
+func TestModifyResponseHeaders_NilConfigIsNoop(t *testing.T) {
+	resp := &http.Response{Header: make(http.Header)}
+	resp.Header.Set("X-Existing", "value")
+
+	ModifyResponseHeaders(resp, nil)
+
+	if resp.Header.Get("X-Existing") != "value" {
+		t.Error("expected headers to be left untouched when cfg is nil")
+	}
+}
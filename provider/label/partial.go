@@ -9,6 +9,71 @@ import (
 	"github.com/containous/flaeg"
 	"github.com/containous/traefik/log"
 	"github.com/containous/traefik/types"
+	"github.com/containous/traefik/utils"
+)
+
+// Rate limit modes.
+const (
+	// DefaultFrontendRateLimitMode is the in-memory, per-instance rate limiting mode.
+	DefaultFrontendRateLimitMode = "local"
+	// RateLimitModeRedis evaluates rate limits atomically against a shared Redis instance.
+	RateLimitModeRedis = "redis"
+)
+
+// TraefikFrontendRateLimitMode and TraefikFrontendRateLimitRedisAddress configure distributed rate limiting.
+const (
+	TraefikFrontendRateLimitMode         = Prefix + "frontend.rateLimit.mode"
+	TraefikFrontendRateLimitRedisAddress = Prefix + "frontend.rateLimit.redis.address"
+)
+
+// SuffixErrorPageTemplate, SuffixErrorPagePassUpstreamBody and
+// SuffixErrorPageContentType extend an error page with an inline Go template,
+// rendered in place of a sub-request to SuffixErrorPageBackend.
+const (
+	SuffixErrorPageTemplate         = "template"
+	SuffixErrorPagePassUpstreamBody = "passUpstreamBody"
+	SuffixErrorPageContentType      = "contentType"
+)
+
+// Health check types.
+const (
+	// DefaultBackendHealthCheckType is the default, pre-existing HTTP health check.
+	DefaultBackendHealthCheckType = HealthCheckTypeHTTP
+	HealthCheckTypeHTTP           = "http"
+	HealthCheckTypeGRPC           = "grpc"
+	HealthCheckTypeTCP            = "tcp"
+)
+
+// TraefikBackendHealthCheckType, TraefikBackendHealthCheckGRPCService and
+// TraefikBackendHealthCheckGRPCTLS* configure gRPC and TCP health checks
+// alongside the pre-existing HTTP health check.
+const (
+	TraefikBackendHealthCheckType                      = Prefix + "backend.healthcheck.type"
+	TraefikBackendHealthCheckGRPCService               = Prefix + "backend.healthcheck.grpc.service"
+	TraefikBackendHealthCheckGRPCTLS                   = Prefix + "backend.healthcheck.grpc.tls"
+	TraefikBackendHealthCheckGRPCTLSInsecureSkipVerify = Prefix + "backend.healthcheck.grpc.tls.insecureSkipVerify"
+)
+
+// Load balancer methods.
+const (
+	LoadBalancerMethodWeighted       = "weighted"
+	LoadBalancerMethodConsistentHash = "consistentHash"
+)
+
+// TraefikBackendLoadBalancerHashKey selects the request attribute
+// consistent-hash load balancing keys on.
+const TraefikBackendLoadBalancerHashKey = Prefix + "backend.loadbalancer.hashKey"
+
+// TraefikFrontendRequestHeaders* and TraefikFrontendResponseHeaders* configure
+// header add/remove/rename operations, in addition to the static overwrite
+// already supported by CustomRequestHeaders/CustomResponseHeaders.
+const (
+	TraefikFrontendRequestHeadersAdd     = Prefix + "frontend.headers.requestHeaders.add."
+	TraefikFrontendRequestHeadersRemove  = Prefix + "frontend.headers.requestHeaders.remove."
+	TraefikFrontendRequestHeadersRename  = Prefix + "frontend.headers.requestHeaders.rename."
+	TraefikFrontendResponseHeadersAdd    = Prefix + "frontend.headers.responseHeaders.add."
+	TraefikFrontendResponseHeadersRemove = Prefix + "frontend.headers.responseHeaders.remove."
+	TraefikFrontendResponseHeadersRename = Prefix + "frontend.headers.responseHeaders.rename."
 )
 
 // GetWhiteList Create white list from labels
@@ -97,6 +162,17 @@ func ParseErrorPages(labels map[string]string, labelPrefix string, labelRegex *r
 				ep.Query = value
 			case SuffixErrorPageBackend:
 				ep.Backend = value
+			case SuffixErrorPageTemplate:
+				ep.Template = value
+			case SuffixErrorPagePassUpstreamBody:
+				passUpstreamBody, err := strconv.ParseBool(value)
+				if err != nil {
+					log.Errorf("Unable to parse %q: %q. %v", lblName, value, err)
+					continue
+				}
+				ep.PassUpstreamBody = passUpstreamBody
+			case SuffixErrorPageContentType:
+				ep.ContentType = value
 			default:
 				log.Errorf("Invalid page error label: %s", lblName)
 				continue
@@ -114,12 +190,31 @@ func GetRateLimit(labels map[string]string) *types.RateLimit {
 		return nil
 	}
 
+	if _, err := utils.NewExtractor(extractorFunc); err != nil {
+		log.Errorf("Invalid rate limit extractor func %q: %v", extractorFunc, err)
+	}
+
 	prefix := Prefix + BaseFrontendRateLimit
 	limits := ParseRateSets(labels, prefix, RegexpFrontendRateLimit)
 
 	return &types.RateLimit{
 		ExtractorFunc: extractorFunc,
 		RateSet:       limits,
+		Mode:          GetStringValue(labels, TraefikFrontendRateLimitMode, DefaultFrontendRateLimitMode),
+		Redis:         getRateLimitRedis(labels),
+	}
+}
+
+// getRateLimitRedis builds the Redis configuration backing a distributed rate
+// limit, or nil when no redis address label is set. The rate limit mode
+// label is not consulted.
+func getRateLimitRedis(labels map[string]string) *types.Redis {
+	if !Has(labels, TraefikFrontendRateLimitRedisAddress) {
+		return nil
+	}
+
+	return &types.Redis{
+		Endpoint: GetStringValue(labels, TraefikFrontendRateLimitRedisAddress, ""),
 	}
 }
 
@@ -206,6 +301,11 @@ func GetHeaders(labels map[string]string) *types.Headers {
 		CustomBrowserXSSValue:   GetStringValue(labels, TraefikFrontendCustomBrowserXSSValue, ""),
 	}
 
+	headers.RequestHeaders = getHeaderManipulation(labels,
+		TraefikFrontendRequestHeadersAdd, TraefikFrontendRequestHeadersRemove, TraefikFrontendRequestHeadersRename)
+	headers.ResponseHeaders = getHeaderManipulation(labels,
+		TraefikFrontendResponseHeadersAdd, TraefikFrontendResponseHeadersRemove, TraefikFrontendResponseHeadersRename)
+
 	if !headers.HasSecureHeadersDefined() && !headers.HasCustomHeadersDefined() {
 		return nil
 	}
@@ -213,6 +313,41 @@ func GetHeaders(labels map[string]string) *types.Headers {
 	return headers
 }
 
+// getHeaderManipulation builds a HeaderManipulation from the add/remove/rename
+// label families rooted at addPrefix, removePrefix and renamePrefix. Each
+// family is one label per entry (e.g. addPrefix+"X-Custom=value"), so, unlike
+// most other Get* helpers in this file, they can't be read with GetMapValue
+// and are instead found by scanning labels for the prefix.
+func getHeaderManipulation(labels map[string]string, addPrefix, removePrefix, renamePrefix string) *types.HeaderManipulation {
+	var remove []string
+	add := make(map[string]string)
+	rename := make(map[string]string)
+
+	for lblName, lblValue := range labels {
+		switch {
+		case strings.HasPrefix(lblName, addPrefix):
+			add[strings.TrimPrefix(lblName, addPrefix)] = lblValue
+		case strings.HasPrefix(lblName, renamePrefix):
+			rename[strings.TrimPrefix(lblName, renamePrefix)] = lblValue
+		case strings.HasPrefix(lblName, removePrefix):
+			remove = append(remove, strings.TrimPrefix(lblName, removePrefix))
+		}
+	}
+
+	if len(add) == 0 && len(remove) == 0 && len(rename) == 0 {
+		return nil
+	}
+
+	manipulation := &types.HeaderManipulation{Remove: remove}
+	if len(add) > 0 {
+		manipulation.Add = add
+	}
+	if len(rename) > 0 {
+		manipulation.Rename = rename
+	}
+	return manipulation
+}
+
 // GetMaxConn Create max connection from labels
 func GetMaxConn(labels map[string]string) *types.MaxConn {
 	amount := GetInt64Value(labels, TraefikBackendMaxConnAmount, math.MinInt64)
@@ -222,6 +357,10 @@ func GetMaxConn(labels map[string]string) *types.MaxConn {
 		return nil
 	}
 
+	if _, err := utils.NewExtractor(extractorFunc); err != nil {
+		log.Errorf("Invalid max connection extractor func %q: %v", extractorFunc, err)
+	}
+
 	return &types.MaxConn{
 		Amount:        amount,
 		ExtractorFunc: extractorFunc,
@@ -230,24 +369,53 @@ func GetMaxConn(labels map[string]string) *types.MaxConn {
 
 // GetHealthCheck Create health check from labels
 func GetHealthCheck(labels map[string]string) *types.HealthCheck {
-	path := GetStringValue(labels, TraefikBackendHealthCheckPath, "")
-	if len(path) == 0 {
+	checkType := GetStringValue(labels, TraefikBackendHealthCheckType, DefaultBackendHealthCheckType)
+	port := GetIntValue(labels, TraefikBackendHealthCheckPort, DefaultBackendHealthCheckPort)
+	interval := GetStringValue(labels, TraefikBackendHealthCheckInterval, "")
+
+	switch checkType {
+	case HealthCheckTypeGRPC:
+		return &types.HealthCheck{
+			Type:        checkType,
+			GRPCService: GetStringValue(labels, TraefikBackendHealthCheckGRPCService, ""),
+			Port:        port,
+			Interval:    interval,
+			Hostname:    GetStringValue(labels, TraefikBackendHealthCheckHostname, ""),
+			TLS:         getHealthCheckTLS(labels),
+		}
+	case HealthCheckTypeTCP:
+		return &types.HealthCheck{
+			Type:     checkType,
+			Port:     port,
+			Interval: interval,
+		}
+	default:
+		path := GetStringValue(labels, TraefikBackendHealthCheckPath, "")
+		if len(path) == 0 {
+			return nil
+		}
+
+		return &types.HealthCheck{
+			Type:     checkType,
+			Scheme:   GetStringValue(labels, TraefikBackendHealthCheckScheme, ""),
+			Path:     path,
+			Port:     port,
+			Interval: interval,
+			Hostname: GetStringValue(labels, TraefikBackendHealthCheckHostname, ""),
+			Headers:  GetMapValue(labels, TraefikBackendHealthCheckHeaders),
+		}
+	}
+}
+
+// getHealthCheckTLS builds the TLS configuration for a gRPC health check, or
+// nil when the backend dials in plaintext.
+func getHealthCheckTLS(labels map[string]string) *types.ClientTLS {
+	if !Has(labels, TraefikBackendHealthCheckGRPCTLS) {
 		return nil
 	}
 
-	scheme := GetStringValue(labels, TraefikBackendHealthCheckScheme, "")
-	port := GetIntValue(labels, TraefikBackendHealthCheckPort, DefaultBackendHealthCheckPort)
-	interval := GetStringValue(labels, TraefikBackendHealthCheckInterval, "")
-	hostname := GetStringValue(labels, TraefikBackendHealthCheckHostname, "")
-	headers := GetMapValue(labels, TraefikBackendHealthCheckHeaders)
-
-	return &types.HealthCheck{
-		Scheme:   scheme,
-		Path:     path,
-		Port:     port,
-		Interval: interval,
-		Hostname: hostname,
-		Headers:  headers,
+	return &types.ClientTLS{
+		InsecureSkipVerify: GetBoolValue(labels, TraefikBackendHealthCheckGRPCTLSInsecureSkipVerify, false),
 	}
 }
 
@@ -284,8 +452,9 @@ func GetLoadBalancer(labels map[string]string) *types.LoadBalancer {
 	method := GetStringValue(labels, TraefikBackendLoadBalancerMethod, DefaultBackendLoadBalancerMethod)
 
 	lb := &types.LoadBalancer{
-		Method: method,
-		Sticky: getSticky(labels),
+		Method:  method,
+		Sticky:  getSticky(labels),
+		HashKey: GetStringValue(labels, TraefikBackendLoadBalancerHashKey, ""),
 	}
 
 	if GetBoolValue(labels, TraefikBackendLoadBalancerStickiness, false) {
@@ -296,6 +465,13 @@ func GetLoadBalancer(labels map[string]string) *types.LoadBalancer {
 	return lb
 }
 
+// GetServerWeight returns the weight configured for serverName via
+// traefik.backend.server.<serverName>.weight, or defaultWeight when unset or invalid.
+func GetServerWeight(labels map[string]string, serverName string, defaultWeight int) int {
+	label := Prefix + "backend.server." + serverName + ".weight"
+	return GetIntValue(labels, label, defaultWeight)
+}
+
 // TODO: Deprecated
 // replaced by Stickiness
 // Deprecated
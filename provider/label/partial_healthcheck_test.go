@@ -0,0 +1,58 @@
+package label
+
+import "testing"
+
+func TestGetHealthCheck_DefaultsToHTTP(t *testing.T) {
+	labels := map[string]string{
+		TraefikBackendHealthCheckPath: "/ping",
+	}
+
+	hc := GetHealthCheck(labels)
+	if hc == nil {
+		t.Fatal("expected a non-nil HealthCheck when a path is set")
+	}
+	if hc.Type != HealthCheckTypeHTTP {
+		t.Errorf("expected default Type %q, got %q", HealthCheckTypeHTTP, hc.Type)
+	}
+	if hc.Path != "/ping" {
+		t.Errorf("expected Path %q, got %q", "/ping", hc.Path)
+	}
+}
+
+func TestGetHealthCheck_HTTPWithoutPathIsNil(t *testing.T) {
+	if hc := GetHealthCheck(map[string]string{}); hc != nil {
+		t.Errorf("expected a nil HealthCheck when no path is configured, got %+v", hc)
+	}
+}
+
+func TestGetHealthCheck_GRPC(t *testing.T) {
+	labels := map[string]string{
+		TraefikBackendHealthCheckType:        HealthCheckTypeGRPC,
+		TraefikBackendHealthCheckGRPCService: "my.Service",
+	}
+
+	hc := GetHealthCheck(labels)
+	if hc == nil {
+		t.Fatal("expected a non-nil HealthCheck for a grpc check")
+	}
+	if hc.Type != HealthCheckTypeGRPC {
+		t.Errorf("expected Type %q, got %q", HealthCheckTypeGRPC, hc.Type)
+	}
+	if hc.GRPCService != "my.Service" {
+		t.Errorf("expected GRPCService %q, got %q", "my.Service", hc.GRPCService)
+	}
+}
+
+func TestGetHealthCheck_TCP(t *testing.T) {
+	labels := map[string]string{
+		TraefikBackendHealthCheckType: HealthCheckTypeTCP,
+	}
+
+	hc := GetHealthCheck(labels)
+	if hc == nil {
+		t.Fatal("expected a non-nil HealthCheck for a tcp check")
+	}
+	if hc.Type != HealthCheckTypeTCP {
+		t.Errorf("expected Type %q, got %q", HealthCheckTypeTCP, hc.Type)
+	}
+}
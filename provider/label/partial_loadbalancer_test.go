@@ -0,0 +1,58 @@
+package label
+
+import "testing"
+
+func TestGetLoadBalancer_DefaultsWhenOnlyPrefixSet(t *testing.T) {
+	labels := map[string]string{
+		TraefikBackendLoadBalancerHashKey: "client.ip",
+	}
+
+	lb := GetLoadBalancer(labels)
+	if lb == nil {
+		t.Fatal("expected a non-nil LoadBalancer when a loadbalancer label is set")
+	}
+	if lb.Method != DefaultBackendLoadBalancerMethod {
+		t.Errorf("expected default Method %q, got %q", DefaultBackendLoadBalancerMethod, lb.Method)
+	}
+	if lb.HashKey != "client.ip" {
+		t.Errorf("expected HashKey %q, got %q", "client.ip", lb.HashKey)
+	}
+}
+
+func TestGetLoadBalancer_Weighted(t *testing.T) {
+	labels := map[string]string{
+		TraefikBackendLoadBalancerMethod: LoadBalancerMethodWeighted,
+	}
+
+	lb := GetLoadBalancer(labels)
+	if lb == nil {
+		t.Fatal("expected a non-nil LoadBalancer")
+	}
+	if lb.Method != LoadBalancerMethodWeighted {
+		t.Errorf("expected Method %q, got %q", LoadBalancerMethodWeighted, lb.Method)
+	}
+}
+
+func TestGetLoadBalancer_Stickiness(t *testing.T) {
+	labels := map[string]string{
+		TraefikBackendLoadBalancerMethod:     LoadBalancerMethodConsistentHash,
+		TraefikBackendLoadBalancerStickiness: "true",
+	}
+
+	lb := GetLoadBalancer(labels)
+	if lb == nil {
+		t.Fatal("expected a non-nil LoadBalancer")
+	}
+	if lb.Stickiness == nil {
+		t.Fatal("expected Stickiness to be set")
+	}
+	if lb.Stickiness.CookieName != DefaultBackendLoadbalancerStickinessCookieName {
+		t.Errorf("expected default cookie name %q, got %q", DefaultBackendLoadbalancerStickinessCookieName, lb.Stickiness.CookieName)
+	}
+}
+
+func TestGetLoadBalancer_NilWhenNoLabelsSet(t *testing.T) {
+	if lb := GetLoadBalancer(map[string]string{}); lb != nil {
+		t.Errorf("expected a nil LoadBalancer for an empty label set, got %+v", lb)
+	}
+}
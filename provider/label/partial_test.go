@@ -0,0 +1,44 @@
+package label
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/containous/traefik/types"
+)
+
+func TestGetHeaders_AddRenameRemovePerEntryLabels(t *testing.T) {
+	labels := map[string]string{
+		TraefikFrontendRequestHeadersAdd + "X-Correlation-Id":   "abc123",
+		TraefikFrontendRequestHeadersRemove + "X-Drop-Me":       "",
+		TraefikFrontendRequestHeadersRename + "X-Old-Name":      "X-New-Name",
+		TraefikFrontendResponseHeadersAdd + "X-Response-Custom": "value",
+	}
+
+	headers := GetHeaders(labels)
+	if headers == nil {
+		t.Fatal("expected non-nil Headers when request/response header labels are set")
+	}
+
+	wantRequest := &types.HeaderManipulation{
+		Add:    map[string]string{"X-Correlation-Id": "abc123"},
+		Remove: []string{"X-Drop-Me"},
+		Rename: map[string]string{"X-Old-Name": "X-New-Name"},
+	}
+	if !reflect.DeepEqual(headers.RequestHeaders, wantRequest) {
+		t.Errorf("RequestHeaders = %+v, want %+v", headers.RequestHeaders, wantRequest)
+	}
+
+	wantResponse := &types.HeaderManipulation{
+		Add: map[string]string{"X-Response-Custom": "value"},
+	}
+	if !reflect.DeepEqual(headers.ResponseHeaders, wantResponse) {
+		t.Errorf("ResponseHeaders = %+v, want %+v", headers.ResponseHeaders, wantResponse)
+	}
+}
+
+func TestGetHeaders_NilWhenNothingConfigured(t *testing.T) {
+	if headers := GetHeaders(map[string]string{}); headers != nil {
+		t.Errorf("expected nil Headers for an empty label set, got %+v", headers)
+	}
+}
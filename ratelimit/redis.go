@@ -0,0 +1,120 @@
+// Package ratelimit provides rate limiting backends shared across frontends.
+package ratelimit
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/containous/traefik/log"
+	"github.com/containous/traefik/types"
+	redis "github.com/go-redis/redis"
+)
+
+// tokenBucketScript implements a token-bucket rate limiter atomically: it
+// refills the bucket based on elapsed time since the last hit, then consumes
+// one token if available. KEYS[1] is the bucket key, ARGV is
+// [capacity, refillPerSecond, nowMillisecond].
+const tokenBucketScript = `
+local key = KEYS[1]
+local capacity = tonumber(ARGV[1])
+local refillPerSecond = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local bucket = redis.call("HMGET", key, "tokens", "timestamp")
+local tokens = tonumber(bucket[1])
+local timestamp = tonumber(bucket[2])
+
+if tokens == nil then
+	tokens = capacity
+	timestamp = now
+end
+
+local elapsed = math.max(0, now - timestamp) / 1000
+tokens = math.min(capacity, tokens + elapsed * refillPerSecond)
+
+local allowed = 0
+if tokens >= 1 then
+	allowed = 1
+	tokens = tokens - 1
+end
+
+redis.call("HMSET", key, "tokens", tokens, "timestamp", now)
+redis.call("PEXPIRE", key, math.ceil(capacity / refillPerSecond * 1000) + 1000)
+
+return allowed
+`
+
+// scriptRunner evaluates the token bucket script against a backing store.
+// It is satisfied by redisScriptRunner in production and by a fake in
+// tests, so Allow can be exercised without a real Redis instance.
+type scriptRunner interface {
+	Run(key string, capacity int64, refillPerSecond float64, nowMillisecond int64) (int64, error)
+}
+
+// redisScriptRunner runs tokenBucketScript against a real Redis instance.
+type redisScriptRunner struct {
+	client *redis.Client
+	script *redis.Script
+}
+
+func (r *redisScriptRunner) Run(key string, capacity int64, refillPerSecond float64, nowMillisecond int64) (int64, error) {
+	result, err := r.script.Run(r.client, []string{key}, capacity, refillPerSecond, nowMillisecond).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	allowed, ok := result.(int64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected result from rate limit script: %v", result)
+	}
+
+	return allowed, nil
+}
+
+// RedisLimiter is a token-bucket rate limiter whose state is shared across
+// Traefik instances via Redis, so that a rate limit configured on a frontend
+// is enforced consistently no matter which instance a request lands on.
+type RedisLimiter struct {
+	runner    scriptRunner
+	capacity  int64
+	refillPS  float64
+	keyPrefix string
+}
+
+// NewRedisLimiter creates a RedisLimiter for the given rate, backed by the
+// Redis instance described by config.
+func NewRedisLimiter(config *types.Redis, rate *types.Rate, keyPrefix string) (*RedisLimiter, error) {
+	if config == nil || len(config.Endpoint) == 0 {
+		return nil, fmt.Errorf("missing redis endpoint for distributed rate limit")
+	}
+	period := time.Duration(rate.Period)
+	if period <= 0 {
+		return nil, fmt.Errorf("invalid rate limit period: %s", period)
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr: config.Endpoint,
+	})
+
+	return &RedisLimiter{
+		runner: &redisScriptRunner{
+			client: client,
+			script: redis.NewScript(tokenBucketScript),
+		},
+		capacity:  rate.Burst,
+		refillPS:  float64(rate.Average) / period.Seconds(),
+		keyPrefix: keyPrefix,
+	}, nil
+}
+
+// Allow reports whether a request identified by key is within the rate
+// limit, consuming a token from its bucket if so.
+func (l *RedisLimiter) Allow(key string, nowMillisecond int64) (bool, error) {
+	allowed, err := l.runner.Run(l.keyPrefix+key, l.capacity, l.refillPS, nowMillisecond)
+	if err != nil {
+		log.Errorf("Unable to evaluate distributed rate limit for %q: %v", key, err)
+		return false, err
+	}
+
+	return allowed == 1, nil
+}
@@ -0,0 +1,130 @@
+package ratelimit
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/containous/traefik/types"
+)
+
+// fakeScriptRunner mirrors the token-bucket math implemented by
+// tokenBucketScript in Go, so Allow's behavior can be verified without a
+// real Redis instance.
+type fakeScriptRunner struct {
+	buckets map[string]fakeBucket
+	keys    []string
+	err     error
+}
+
+type fakeBucket struct {
+	tokens    float64
+	timestamp int64
+}
+
+func (f *fakeScriptRunner) Run(key string, capacity int64, refillPerSecond float64, nowMillisecond int64) (int64, error) {
+	f.keys = append(f.keys, key)
+	if f.err != nil {
+		return 0, f.err
+	}
+
+	if f.buckets == nil {
+		f.buckets = make(map[string]fakeBucket)
+	}
+
+	bucket, ok := f.buckets[key]
+	if !ok {
+		bucket = fakeBucket{tokens: float64(capacity), timestamp: nowMillisecond}
+	}
+
+	elapsed := float64(nowMillisecond-bucket.timestamp) / 1000
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	tokens := bucket.tokens + elapsed*refillPerSecond
+	if tokens > float64(capacity) {
+		tokens = float64(capacity)
+	}
+
+	var allowed int64
+	if tokens >= 1 {
+		allowed = 1
+		tokens--
+	}
+
+	f.buckets[key] = fakeBucket{tokens: tokens, timestamp: nowMillisecond}
+
+	return allowed, nil
+}
+
+func TestRedisLimiter_AllowsUpToCapacityThenDenies(t *testing.T) {
+	runner := &fakeScriptRunner{}
+	limiter := &RedisLimiter{runner: runner, capacity: 2, refillPS: 0, keyPrefix: "rl_"}
+
+	for i := 0; i < 2; i++ {
+		ok, err := limiter.Allow("client-1", 1000)
+		if err != nil {
+			t.Fatalf("Allow: %v", err)
+		}
+		if !ok {
+			t.Fatalf("expected request %d to be allowed within capacity", i+1)
+		}
+	}
+
+	ok, err := limiter.Allow("client-1", 1000)
+	if err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	if ok {
+		t.Error("expected request beyond capacity to be denied")
+	}
+}
+
+func TestRedisLimiter_RefillsOverTime(t *testing.T) {
+	runner := &fakeScriptRunner{}
+	limiter := &RedisLimiter{runner: runner, capacity: 1, refillPS: 1, keyPrefix: "rl_"}
+
+	ok, err := limiter.Allow("client-1", 0)
+	if err != nil || !ok {
+		t.Fatalf("expected first request to be allowed, got ok=%v err=%v", ok, err)
+	}
+
+	if ok, _ := limiter.Allow("client-1", 100); ok {
+		t.Error("expected request before refill to be denied")
+	}
+
+	ok, err = limiter.Allow("client-1", 1000)
+	if err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	if !ok {
+		t.Error("expected request after a full second to be allowed by refill")
+	}
+}
+
+func TestRedisLimiter_PrefixesKey(t *testing.T) {
+	runner := &fakeScriptRunner{}
+	limiter := &RedisLimiter{runner: runner, capacity: 1, refillPS: 1, keyPrefix: "frontend1_"}
+
+	if _, err := limiter.Allow("client-1", 0); err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+
+	if len(runner.keys) != 1 || runner.keys[0] != "frontend1_client-1" {
+		t.Errorf("expected runner to be called with prefixed key %q, got %v", "frontend1_client-1", runner.keys)
+	}
+}
+
+func TestRedisLimiter_PropagatesRunnerError(t *testing.T) {
+	runner := &fakeScriptRunner{err: fmt.Errorf("connection refused")}
+	limiter := &RedisLimiter{runner: runner, capacity: 1, refillPS: 1, keyPrefix: "rl_"}
+
+	if _, err := limiter.Allow("client-1", 0); err == nil {
+		t.Error("expected the runner error to be propagated")
+	}
+}
+
+func TestNewRedisLimiter_RequiresEndpoint(t *testing.T) {
+	if _, err := NewRedisLimiter(nil, &types.Rate{}, "rl_"); err == nil {
+		t.Error("expected an error when redis config is nil")
+	}
+}
@@ -0,0 +1,168 @@
+package types
+
+import (
+	"github.com/containous/flaeg"
+)
+
+// RateLimit holds rate limiting configuration for a given frontend
+type RateLimit struct {
+	RateSet       map[string]*Rate `json:"rateset,omitempty"`
+	ExtractorFunc string           `json:"extractorFunc,omitempty"`
+	Mode          string           `json:"mode,omitempty"`
+	Redis         *Redis           `json:"redis,omitempty"`
+}
+
+// Rate holds a rate limiting configuration for a given time period
+type Rate struct {
+	Period  flaeg.Duration `json:"period,omitempty"`
+	Average int64          `json:"average,omitempty"`
+	Burst   int64          `json:"burst,omitempty"`
+}
+
+// Redis holds the connection details of the Redis instance backing a distributed rate limiter
+type Redis struct {
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// LoadBalancer holds the LoadBalancer configuration
+type LoadBalancer struct {
+	Method string `json:"method,omitempty"`
+	Sticky bool   `json:"sticky,omitempty"`
+
+	Stickiness *Stickiness `json:"stickiness,omitempty"`
+
+	// HashKey selects the request attribute consistent-hash load balancing
+	// keys on, e.g. "header.X-User", "cookie.sid" or "client.ip". Only used
+	// when Method is "consistentHash".
+	HashKey string `json:"hashKey,omitempty"`
+}
+
+// Stickiness holds the sticky session configuration
+type Stickiness struct {
+	CookieName string `json:"cookieName,omitempty"`
+}
+
+// ClientTLS holds the minimal TLS client settings needed to dial a backend
+// over TLS, e.g. for a gRPC health check.
+type ClientTLS struct {
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// ErrorPage holds custom error page configuration
+type ErrorPage struct {
+	Status  []string `json:"status,omitempty"`
+	Query   string   `json:"query,omitempty"`
+	Backend string   `json:"backend,omitempty"`
+
+	// Template, when set, is rendered inline instead of sub-requesting Backend.
+	// It is executed with an ErrorPageContext and may reference
+	// {{.StatusCode}}, {{.RequestID}}, {{.Host}}, and {{.UpstreamBody}}.
+	Template string `json:"template,omitempty"`
+	// PassUpstreamBody makes the failed upstream response body available to
+	// Template as {{.UpstreamBody}}.
+	PassUpstreamBody bool `json:"passUpstreamBody,omitempty"`
+	// ContentType overrides the Content-Type set on the rendered error page.
+	ContentType string `json:"contentType,omitempty"`
+}
+
+// ErrorPageContext is the data made available to an ErrorPage.Template.
+type ErrorPageContext struct {
+	StatusCode   int
+	RequestID    string
+	Host         string
+	UpstreamBody string
+}
+
+// HealthCheck holds the HealthCheck configuration
+type HealthCheck struct {
+	Scheme   string            `json:"scheme,omitempty"`
+	Path     string            `json:"path,omitempty"`
+	Port     int               `json:"port,omitempty"`
+	Interval string            `json:"interval,omitempty"`
+	Hostname string            `json:"hostname,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+
+	// Type selects the health check protocol: "http" (default), "grpc" or "tcp".
+	Type string `json:"type,omitempty"`
+	// GRPCService is the service name passed to the gRPC Health Checking
+	// Protocol's Check RPC. Only used when Type is "grpc".
+	GRPCService string `json:"grpcService,omitempty"`
+	// TLS dials the health check connection over TLS when set. Only used
+	// when Type is "grpc".
+	TLS *ClientTLS `json:"tls,omitempty"`
+}
+
+// Headers holds the Header middleware configuration
+type Headers struct {
+	CustomRequestHeaders    map[string]string   `json:"customRequestHeaders,omitempty"`
+	CustomResponseHeaders   map[string]string   `json:"customResponseHeaders,omitempty"`
+	RequestHeaders          *HeaderManipulation `json:"requestHeaders,omitempty"`
+	ResponseHeaders         *HeaderManipulation `json:"responseHeaders,omitempty"`
+	AllowedHosts            []string            `json:"allowedHosts,omitempty"`
+	HostsProxyHeaders       []string            `json:"hostsProxyHeaders,omitempty"`
+	SSLRedirect             bool                `json:"sslRedirect,omitempty"`
+	SSLTemporaryRedirect    bool                `json:"sslTemporaryRedirect,omitempty"`
+	SSLHost                 string              `json:"sslHost,omitempty"`
+	SSLProxyHeaders         map[string]string   `json:"sslProxyHeaders,omitempty"`
+	SSLForceHost            bool                `json:"sslForceHost,omitempty"`
+	STSSeconds              int64               `json:"stsSeconds,omitempty"`
+	STSIncludeSubdomains    bool                `json:"stsIncludeSubdomains,omitempty"`
+	STSPreload              bool                `json:"stsPreload,omitempty"`
+	ForceSTSHeader          bool                `json:"forceSTSHeader,omitempty"`
+	FrameDeny               bool                `json:"frameDeny,omitempty"`
+	CustomFrameOptionsValue string              `json:"customFrameOptionsValue,omitempty"`
+	ContentTypeNosniff      bool                `json:"contentTypeNosniff,omitempty"`
+	BrowserXSSFilter        bool                `json:"browserXssFilter,omitempty"`
+	CustomBrowserXSSValue   string              `json:"customBrowserXSSValue,omitempty"`
+	ContentSecurityPolicy   string              `json:"contentSecurityPolicy,omitempty"`
+	PublicKey               string              `json:"publicKey,omitempty"`
+	ReferrerPolicy          string              `json:"referrerPolicy,omitempty"`
+	IsDevelopment           bool                `json:"isDevelopment,omitempty"`
+}
+
+// HasCustomHeadersDefined checks to see if any of the custom header elements have been set
+func (h *Headers) HasCustomHeadersDefined() bool {
+	return len(h.CustomResponseHeaders) != 0 ||
+		len(h.CustomRequestHeaders) != 0 ||
+		h.RequestHeaders.isDefined() ||
+		h.ResponseHeaders.isDefined()
+}
+
+// HasSecureHeadersDefined checks to see if any of the secure header elements have been set
+func (h *Headers) HasSecureHeadersDefined() bool {
+	return len(h.AllowedHosts) != 0 ||
+		len(h.HostsProxyHeaders) != 0 ||
+		h.SSLRedirect ||
+		h.SSLTemporaryRedirect ||
+		h.SSLForceHost ||
+		len(h.SSLHost) != 0 ||
+		len(h.SSLProxyHeaders) != 0 ||
+		h.STSSeconds != 0 ||
+		h.STSIncludeSubdomains ||
+		h.STSPreload ||
+		h.ForceSTSHeader ||
+		h.FrameDeny ||
+		len(h.CustomFrameOptionsValue) != 0 ||
+		h.ContentTypeNosniff ||
+		h.BrowserXSSFilter ||
+		len(h.CustomBrowserXSSValue) != 0 ||
+		len(h.ContentSecurityPolicy) != 0 ||
+		len(h.PublicKey) != 0 ||
+		len(h.ReferrerPolicy) != 0 ||
+		h.IsDevelopment
+}
+
+// HeaderManipulation describes add/remove/rename operations applied to a set
+// of headers, on top of any static overwrite from CustomRequestHeaders /
+// CustomResponseHeaders. Operations are applied in the order remove, rename,
+// the static CustomRequestHeaders/CustomResponseHeaders overwrite, then add;
+// add appends a value rather than replacing existing ones.
+type HeaderManipulation struct {
+	Add    map[string]string `json:"add,omitempty"`
+	Remove []string          `json:"remove,omitempty"`
+	Rename map[string]string `json:"rename,omitempty"`
+}
+
+func (h *HeaderManipulation) isDefined() bool {
+	return h != nil && (len(h.Add) != 0 || len(h.Remove) != 0 || len(h.Rename) != 0)
+}
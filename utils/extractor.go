@@ -0,0 +1,103 @@
+// Package utils provides small stateless helpers shared by providers and middlewares.
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ExtractorFunc extracts a rate-limit or max-connection key from an incoming
+// request, along with the amount (typically 1) that a single request counts
+// for.
+type ExtractorFunc func(req *http.Request) (token string, amount int64, err error)
+
+// NewExtractor builds an ExtractorFunc from an expression.
+//
+// Supported expressions:
+//   - client.ip
+//   - request.host
+//   - request.header.<Name>
+//   - jwt.claim.<name>            extracts a claim from an unverified JWT
+//     found in the Authorization header
+//   - cookie.<name>
+//   - composite keys joining any of the above with "+", e.g.
+//     "client.ip+request.header.X-Api-Key"
+func NewExtractor(expression string) (ExtractorFunc, error) {
+	parts := strings.Split(expression, "+")
+
+	extractors := make([]ExtractorFunc, 0, len(parts))
+	for _, part := range parts {
+		extractor, err := newSingleExtractor(part)
+		if err != nil {
+			return nil, err
+		}
+		extractors = append(extractors, extractor)
+	}
+
+	if len(extractors) == 1 {
+		return extractors[0], nil
+	}
+
+	return func(req *http.Request) (string, int64, error) {
+		tokens := make([]string, len(extractors))
+		for i, extractor := range extractors {
+			token, _, err := extractor(req)
+			if err != nil {
+				return "", 0, err
+			}
+			tokens[i] = token
+		}
+		return strings.Join(tokens, "+"), 1, nil
+	}, nil
+}
+
+func newSingleExtractor(expression string) (ExtractorFunc, error) {
+	switch {
+	case expression == "client.ip":
+		return func(req *http.Request) (string, int64, error) {
+			return canonicalIP(req.RemoteAddr), 1, nil
+		}, nil
+
+	case expression == "request.host":
+		return func(req *http.Request) (string, int64, error) {
+			return req.Host, 1, nil
+		}, nil
+
+	case strings.HasPrefix(expression, "request.header."):
+		name := strings.TrimPrefix(expression, "request.header.")
+		return func(req *http.Request) (string, int64, error) {
+			return req.Header.Get(name), 1, nil
+		}, nil
+
+	case strings.HasPrefix(expression, "cookie."):
+		name := strings.TrimPrefix(expression, "cookie.")
+		return func(req *http.Request) (string, int64, error) {
+			cookie, err := req.Cookie(name)
+			if err != nil {
+				return "", 1, fmt.Errorf("missing cookie %q: %v", name, err)
+			}
+			return cookie.Value, 1, nil
+		}, nil
+
+	case strings.HasPrefix(expression, "jwt.claim."):
+		claim := strings.TrimPrefix(expression, "jwt.claim.")
+		return func(req *http.Request) (string, int64, error) {
+			value, err := jwtClaim(req, claim)
+			if err != nil {
+				return "", 1, fmt.Errorf("missing jwt claim %q: %v", claim, err)
+			}
+			return value, 1, nil
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("invalid extractor expression %q", expression)
+	}
+}
+
+func canonicalIP(remoteAddr string) string {
+	if idx := strings.LastIndex(remoteAddr, ":"); idx != -1 {
+		return remoteAddr[:idx]
+	}
+	return remoteAddr
+}
@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewExtractor_ClientIP(t *testing.T) {
+	extractor, err := NewExtractor("client.ip")
+	if err != nil {
+		t.Fatalf("NewExtractor: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "203.0.113.5:54321"
+
+	token, amount, err := extractor(req)
+	if err != nil {
+		t.Fatalf("extractor: %v", err)
+	}
+	if token != "203.0.113.5" {
+		t.Errorf("expected canonical IP without port, got %q", token)
+	}
+	if amount != 1 {
+		t.Errorf("expected amount 1, got %d", amount)
+	}
+}
+
+func TestNewExtractor_RequestHost(t *testing.T) {
+	extractor, err := NewExtractor("request.host")
+	if err != nil {
+		t.Fatalf("NewExtractor: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Host = "example.com"
+
+	token, _, err := extractor(req)
+	if err != nil {
+		t.Fatalf("extractor: %v", err)
+	}
+	if token != "example.com" {
+		t.Errorf("expected %q, got %q", "example.com", token)
+	}
+}
+
+func TestNewExtractor_RequestHeader(t *testing.T) {
+	extractor, err := NewExtractor("request.header.X-Api-Key")
+	if err != nil {
+		t.Fatalf("NewExtractor: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Api-Key", "secret")
+
+	token, _, err := extractor(req)
+	if err != nil {
+		t.Fatalf("extractor: %v", err)
+	}
+	if token != "secret" {
+		t.Errorf("expected %q, got %q", "secret", token)
+	}
+}
+
+func TestNewExtractor_Cookie(t *testing.T) {
+	extractor, err := NewExtractor("cookie.sid")
+	if err != nil {
+		t.Fatalf("NewExtractor: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.AddCookie(&http.Cookie{Name: "sid", Value: "abc123"})
+
+	token, _, err := extractor(req)
+	if err != nil {
+		t.Fatalf("extractor: %v", err)
+	}
+	if token != "abc123" {
+		t.Errorf("expected %q, got %q", "abc123", token)
+	}
+}
+
+func TestNewExtractor_CookieMissingPropagatesError(t *testing.T) {
+	extractor, err := NewExtractor("cookie.sid")
+	if err != nil {
+		t.Fatalf("NewExtractor: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	if _, _, err := extractor(req); err == nil {
+		t.Error("expected an error when the cookie is missing, got nil")
+	}
+}
+
+func TestNewExtractor_JWTClaim(t *testing.T) {
+	extractor, err := NewExtractor("jwt.claim.sub")
+	if err != nil {
+		t.Fatalf("NewExtractor: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer "+fakeJWT(t, map[string]interface{}{"sub": "user-42"}))
+
+	token, _, err := extractor(req)
+	if err != nil {
+		t.Fatalf("extractor: %v", err)
+	}
+	if token != "user-42" {
+		t.Errorf("expected %q, got %q", "user-42", token)
+	}
+}
+
+func TestNewExtractor_JWTClaimMissingPropagatesError(t *testing.T) {
+	extractor, err := NewExtractor("jwt.claim.sub")
+	if err != nil {
+		t.Fatalf("NewExtractor: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	if _, _, err := extractor(req); err == nil {
+		t.Error("expected an error when the Authorization header is absent, got nil")
+	}
+}
+
+func TestNewExtractor_Composite(t *testing.T) {
+	extractor, err := NewExtractor("client.ip+request.header.X-Api-Key")
+	if err != nil {
+		t.Fatalf("NewExtractor: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "198.51.100.1:1234"
+	req.Header.Set("X-Api-Key", "key-1")
+
+	token, _, err := extractor(req)
+	if err != nil {
+		t.Fatalf("extractor: %v", err)
+	}
+	if token != "198.51.100.1+key-1" {
+		t.Errorf("expected composite key %q, got %q", "198.51.100.1+key-1", token)
+	}
+}
+
+func TestNewExtractor_InvalidExpression(t *testing.T) {
+	if _, err := NewExtractor("bogus.expression"); err == nil {
+		t.Error("expected an error for an invalid extractor expression, got nil")
+	}
+}
+
+func fakeJWT(t *testing.T, claims map[string]interface{}) string {
+	t.Helper()
+
+	payloadJSON, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	payload := base64.RawURLEncoding.EncodeToString(payloadJSON)
+	return header + "." + payload + ".sig"
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// jwtClaim reads the named claim out of the JWT carried in the request's
+// Authorization header, without verifying its signature. It is only used to
+// derive a rate limit / max-conn key, never to authenticate the request.
+func jwtClaim(req *http.Request, claim string) (string, error) {
+	auth := req.Header.Get("Authorization")
+	token := strings.TrimPrefix(auth, "Bearer ")
+	if token == auth {
+		return "", fmt.Errorf("missing bearer token")
+	}
+
+	segments := strings.Split(token, ".")
+	if len(segments) != 3 {
+		return "", fmt.Errorf("malformed JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(segments[1])
+	if err != nil {
+		return "", fmt.Errorf("unable to decode JWT payload: %v", err)
+	}
+
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", fmt.Errorf("unable to parse JWT payload: %v", err)
+	}
+
+	value, ok := claims[claim]
+	if !ok {
+		return "", fmt.Errorf("claim %q not present", claim)
+	}
+
+	return fmt.Sprintf("%v", value), nil
+}
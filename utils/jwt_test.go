@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestJwtClaim_MissingAuthorizationHeader(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+
+	if _, err := jwtClaim(req, "sub"); err == nil {
+		t.Error("expected an error when the Authorization header is absent")
+	}
+}
+
+func TestJwtClaim_MalformedToken(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer not-a-jwt")
+
+	if _, err := jwtClaim(req, "sub"); err == nil {
+		t.Error("expected an error for a malformed JWT")
+	}
+}
+
+func TestJwtClaim_UnknownClaim(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer "+fakeJWT(t, map[string]interface{}{"sub": "user-1"}))
+
+	if _, err := jwtClaim(req, "missing"); err == nil {
+		t.Error("expected an error for a claim that is not present")
+	}
+}